@@ -0,0 +1,162 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/insomn14/amass/requests"
+)
+
+// EnumGraph is a typed representation of the asset relationships discovered
+// during an enumeration. It replaces the fragile " --> " formatted record
+// strings that PrintEnumerationSummary used to re-parse, where a stray space
+// or a renamed type label (e.g. "(FQDN)") could silently drop or
+// misattribute data.
+type EnumGraph struct {
+	Target string `json:"target"`
+	Total  int    `json:"total"`
+	ASNs   []ASN  `json:"asns"`
+}
+
+// ASN represents a single Autonomous System discovered during enumeration,
+// along with the netblocks it routes and the FQDNs found beneath it.
+type ASN struct {
+	ID           string     `json:"id"`
+	Organization string     `json:"organization"`
+	Netblocks    []Netblock `json:"netblocks"`
+	FQDNs        []FQDN     `json:"fqdns"`
+}
+
+// Netblock represents a CIDR routed under an ASN.
+type Netblock struct {
+	CIDR string `json:"cidr"`
+}
+
+// FQDN represents a discovered domain name and the IP addresses it resolved
+// to, along with the specific netblock (if known) it was resolved under.
+// The json tags match the jsonFQDN shape JSONLFormat already emits,
+// so JSONFormat's single-document output uses the same field names.
+type FQDN struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+	Netblock  string   `json:"netblock,omitempty"`
+}
+
+// BuildEnumGraph constructs an EnumGraph directly from enumeration output,
+// without going through the " --> " formatted strings that ParseLegacyRecords
+// exists only to support. This is the path real callers should use: each
+// requests.Output carries its own resolved addresses, each tagged with the
+// ASN and netblock it belongs to, so there is no ambiguity to reparse.
+func BuildEnumGraph(total int, outputs []*requests.Output, target string) *EnumGraph {
+	g := &EnumGraph{Target: target, Total: total}
+
+	asnIdx := make(map[int]int)
+	for _, out := range outputs {
+		for _, addr := range out.Addresses {
+			if addr.CIDRStr == "" {
+				continue
+			}
+
+			idx, found := asnIdx[addr.ASN]
+			if !found {
+				g.ASNs = append(g.ASNs, ASN{ID: strconv.Itoa(addr.ASN), Organization: addr.Description})
+				idx = len(g.ASNs) - 1
+				asnIdx[addr.ASN] = idx
+			}
+
+			asn := &g.ASNs[idx]
+			if !hasNetblock(asn.Netblocks, addr.CIDRStr) {
+				asn.Netblocks = append(asn.Netblocks, Netblock{CIDR: addr.CIDRStr})
+			}
+
+			if fqdn := findFQDN(asn.FQDNs, out.Name); fqdn != nil {
+				fqdn.Addresses = append(fqdn.Addresses, addr.Address.String())
+				continue
+			}
+			asn.FQDNs = append(asn.FQDNs, FQDN{
+				Name:      out.Name,
+				Addresses: []string{addr.Address.String()},
+				Netblock:  addr.CIDRStr,
+			})
+		}
+	}
+
+	return g
+}
+
+func hasNetblock(netblocks []Netblock, cidr string) bool {
+	for _, nb := range netblocks {
+		if nb.CIDR == cidr {
+			return true
+		}
+	}
+	return false
+}
+
+// findFQDN returns the existing entry for name in fqdns, so a host with
+// multiple resolved addresses under the same ASN accumulates into one FQDN
+// instead of one per address - callers rely on len(ASN.FQDNs) as the
+// subdomain count, and JSONLFormat documents one line per FQDN.
+func findFQDN(fqdns []FQDN, name string) *FQDN {
+	for i := range fqdns {
+		if fqdns[i].Name == name {
+			return &fqdns[i]
+		}
+	}
+	return nil
+}
+
+// ParseLegacyRecords rebuilds an EnumGraph from the " --> " formatted record
+// strings this package used to emit and re-parse. It exists only so callers
+// still holding the legacy []string format can migrate incrementally; new
+// code should build an EnumGraph directly from the asset DB instead, since a
+// record stream alone cannot reliably recover which ASN a netblock or FQDN
+// belongs to.
+func ParseLegacyRecords(total int, records []string, target string) *EnumGraph {
+	g := &EnumGraph{Target: target, Total: total}
+
+	asnIdx := make(map[string]int)
+	curASN := ""
+	curNetblock := ""
+
+	for _, record := range records {
+		parts := strings.Split(record, " --> ")
+		if len(parts) < 3 {
+			continue // Skip malformed records
+		}
+
+		left := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[2])
+
+		switch {
+		case strings.HasSuffix(left, " (ASN)") && strings.HasSuffix(value, " (RIROrganization)"):
+			id := strings.TrimSuffix(left, " (ASN)")
+			if _, found := asnIdx[id]; !found {
+				g.ASNs = append(g.ASNs, ASN{ID: id, Organization: strings.TrimSuffix(value, " (RIROrganization)")})
+				asnIdx[id] = len(g.ASNs) - 1
+			}
+			curASN = id
+			curNetblock = ""
+		case strings.HasSuffix(value, " (Netblock)"):
+			// Associate the netblock with whichever ASN record most recently
+			// preceded it, rather than every ASN seen so far.
+			if idx, found := asnIdx[curASN]; found {
+				cidr := strings.TrimSuffix(value, " (Netblock)")
+				g.ASNs[idx].Netblocks = append(g.ASNs[idx].Netblocks, Netblock{CIDR: cidr})
+				curNetblock = cidr
+			}
+		case strings.HasSuffix(left, " (FQDN)") && strings.HasSuffix(value, " (IPAddress)"):
+			if idx, found := asnIdx[curASN]; found {
+				name := strings.TrimSuffix(left, " (FQDN)")
+				addr := strings.TrimSuffix(value, " (IPAddress)")
+				g.ASNs[idx].FQDNs = append(g.ASNs[idx].FQDNs, FQDN{Name: name, Addresses: []string{addr}, Netblock: curNetblock})
+			}
+		}
+	}
+
+	return g
+}
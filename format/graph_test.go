@@ -0,0 +1,80 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomn14/amass/requests"
+)
+
+func TestParseLegacyRecordsAssociatesNetblockWithOwningASN(t *testing.T) {
+	records := []string{
+		"1111 (ASN) --> managed_by --> Org One (RIROrganization)",
+		"1111 (ASN) --> announces --> 192.0.2.0/24 (Netblock)",
+		"one.example.com (FQDN) --> a_record --> 192.0.2.10 (IPAddress)",
+		"2222 (ASN) --> managed_by --> Org Two (RIROrganization)",
+		"2222 (ASN) --> announces --> 198.51.100.0/24 (Netblock)",
+		"two.example.com (FQDN) --> a_record --> 198.51.100.10 (IPAddress)",
+	}
+
+	g := ParseLegacyRecords(2, records, "example.com")
+	if len(g.ASNs) != 2 {
+		t.Fatalf("expected 2 ASNs, got %d", len(g.ASNs))
+	}
+
+	for _, asn := range g.ASNs {
+		if len(asn.Netblocks) != 1 {
+			t.Errorf("ASN %s: expected 1 netblock, got %d", asn.ID, len(asn.Netblocks))
+		}
+		if len(asn.FQDNs) != 1 {
+			t.Errorf("ASN %s: expected 1 FQDN, got %d", asn.ID, len(asn.FQDNs))
+		}
+	}
+}
+
+func TestBuildEnumGraphAccumulatesAddressesForTheSameFQDN(t *testing.T) {
+	addr := func(asn int, cidr, org, ip string) requests.AddressInfo {
+		return requests.AddressInfo{ASN: asn, CIDRStr: cidr, Description: org, Address: net.ParseIP(ip)}
+	}
+
+	outputs := []*requests.Output{
+		{Name: "one.example.com", Addresses: []requests.AddressInfo{
+			addr(1111, "192.0.2.0/24", "Org One", "192.0.2.10"),
+			addr(1111, "192.0.2.0/24", "Org One", "192.0.2.11"),
+		}},
+	}
+
+	g := BuildEnumGraph(1, outputs, "example.com")
+	if len(g.ASNs[0].FQDNs) != 1 {
+		t.Fatalf("expected one.example.com to collapse into a single FQDN entry, got %d", len(g.ASNs[0].FQDNs))
+	}
+	if addrs := g.ASNs[0].FQDNs[0].Addresses; len(addrs) != 2 {
+		t.Fatalf("expected both addresses on the single FQDN entry, got %v", addrs)
+	}
+}
+
+func TestBuildEnumGraphDedupesNetblocksPerASN(t *testing.T) {
+	addr := func(asn int, cidr, org, ip string) requests.AddressInfo {
+		return requests.AddressInfo{ASN: asn, CIDRStr: cidr, Description: org, Address: net.ParseIP(ip)}
+	}
+
+	outputs := []*requests.Output{
+		{Name: "one.example.com", Addresses: []requests.AddressInfo{addr(1111, "192.0.2.0/24", "Org One", "192.0.2.10")}},
+		{Name: "other.example.com", Addresses: []requests.AddressInfo{addr(1111, "192.0.2.0/24", "Org One", "192.0.2.20")}},
+	}
+
+	g := BuildEnumGraph(1, outputs, "example.com")
+	if len(g.ASNs) != 1 {
+		t.Fatalf("expected 1 ASN, got %d", len(g.ASNs))
+	}
+	if len(g.ASNs[0].Netblocks) != 1 {
+		t.Fatalf("expected the shared netblock to be recorded once, got %d", len(g.ASNs[0].Netblocks))
+	}
+	if len(g.ASNs[0].FQDNs) != 2 {
+		t.Fatalf("expected both FQDNs to be recorded, got %d", len(g.ASNs[0].FQDNs))
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OutputFormat renders an EnumGraph in a specific encoding, so enumeration
+// results can be consumed by tools other than a human reading the text
+// report, e.g. piped into a SIEM, a BI tool, or a graph viewer.
+type OutputFormat interface {
+	// Name is the identifier used to select this format, e.g. via -of json,csv.
+	Name() string
+	// Extension is the file extension (without the leading dot) used when
+	// this format is saved alongside the default text report.
+	Extension() string
+	// Write renders g to out.
+	Write(out io.Writer, g *EnumGraph) error
+}
+
+// ParseOutputFormats resolves the names given to the -of flag (e.g.
+// "json,csv,dot") into the corresponding OutputFormat implementations.
+// Unrecognized names are ignored.
+func ParseOutputFormats(names []string) []OutputFormat {
+	var formats []OutputFormat
+
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "", "text":
+			formats = append(formats, TextFormat{})
+		case "json":
+			formats = append(formats, JSONFormat{})
+		case "jsonl":
+			formats = append(formats, JSONLFormat{})
+		case "csv":
+			formats = append(formats, CSVFormat{})
+		case "dot":
+			formats = append(formats, DOTFormat{})
+		}
+	}
+
+	return formats
+}
+
+// TextFormat renders the human-readable report previously produced by
+// SaveASNDetailsToFile.
+type TextFormat struct{}
+
+// Name implements the OutputFormat interface.
+func (TextFormat) Name() string { return "text" }
+
+// Extension implements the OutputFormat interface.
+func (TextFormat) Extension() string { return "txt" }
+
+// Write implements the OutputFormat interface.
+func (TextFormat) Write(out io.Writer, g *EnumGraph) error {
+	for _, asn := range g.ASNs {
+		netblocks := make([]string, len(asn.Netblocks))
+		for i, nb := range asn.Netblocks {
+			netblocks[i] = nb.CIDR
+		}
+		if _, err := fmt.Fprintf(out, "ASN: %s - %s\n\tNetblocks: %s\n\tSubdomains: %d\n",
+			asn.ID, asn.Organization, strings.Join(netblocks, ", "), len(asn.FQDNs)); err != nil {
+			return err
+		}
+
+		for _, f := range asn.FQDNs {
+			for _, addr := range f.Addresses {
+				if _, err := fmt.Fprintf(out, "%s: %s\n", f.Name, addr); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonFQDN is the flattened shape emitted by JSONLFormat, one per line.
+// CSVFormat writes the same fields as columns, without using this type.
+type jsonFQDN struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+	ASN       string   `json:"asn"`
+	Org       string   `json:"organization"`
+	Netblock  string   `json:"netblock,omitempty"`
+}
+
+// JSONFormat renders the full EnumGraph as a single JSON document.
+type JSONFormat struct{}
+
+// Name implements the OutputFormat interface.
+func (JSONFormat) Name() string { return "json" }
+
+// Extension implements the OutputFormat interface.
+func (JSONFormat) Extension() string { return "json" }
+
+// Write implements the OutputFormat interface.
+func (JSONFormat) Write(out io.Writer, g *EnumGraph) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// JSONLFormat renders one JSON object per discovered FQDN, one per line, for
+// streaming consumption by tools such as jq.
+type JSONLFormat struct{}
+
+// Name implements the OutputFormat interface.
+func (JSONLFormat) Name() string { return "jsonl" }
+
+// Extension implements the OutputFormat interface.
+func (JSONLFormat) Extension() string { return "jsonl" }
+
+// Write implements the OutputFormat interface.
+func (JSONLFormat) Write(out io.Writer, g *EnumGraph) error {
+	enc := json.NewEncoder(out)
+
+	for _, asn := range g.ASNs {
+		for _, f := range asn.FQDNs {
+			rec := jsonFQDN{
+				Name:      f.Name,
+				Addresses: f.Addresses,
+				ASN:       asn.ID,
+				Org:       asn.Organization,
+				Netblock:  f.Netblock,
+			}
+			if err := enc.Encode(rec); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CSVFormat renders fqdn,ip,asn,netblock,org rows, one per resolved address.
+type CSVFormat struct{}
+
+// Name implements the OutputFormat interface.
+func (CSVFormat) Name() string { return "csv" }
+
+// Extension implements the OutputFormat interface.
+func (CSVFormat) Extension() string { return "csv" }
+
+// Write implements the OutputFormat interface.
+func (CSVFormat) Write(out io.Writer, g *EnumGraph) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"fqdn", "ip", "asn", "netblock", "org"}); err != nil {
+		return err
+	}
+
+	for _, asn := range g.ASNs {
+		for _, f := range asn.FQDNs {
+			for _, addr := range f.Addresses {
+				if err := w.Write([]string{f.Name, addr, asn.ID, f.Netblock, asn.Organization}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// DOTFormat renders the ASN -> Netblock -> FQDN -> IP relationships as a
+// Graphviz graph, so the discovered attack surface can be visualized.
+type DOTFormat struct{}
+
+// Name implements the OutputFormat interface.
+func (DOTFormat) Name() string { return "dot" }
+
+// Extension implements the OutputFormat interface.
+func (DOTFormat) Extension() string { return "dot" }
+
+// Write implements the OutputFormat interface.
+func (DOTFormat) Write(out io.Writer, g *EnumGraph) error {
+	if _, err := fmt.Fprintln(out, "digraph amass {"); err != nil {
+		return err
+	}
+
+	for _, asn := range g.ASNs {
+		asnNode := fmt.Sprintf("%q", "ASN "+asn.ID)
+		for _, nb := range asn.Netblocks {
+			nbNode := fmt.Sprintf("%q", nb.CIDR)
+			if _, err := fmt.Fprintf(out, "\t%s -> %s;\n", asnNode, nbNode); err != nil {
+				return err
+			}
+		}
+
+		// Draw each FQDN under the netblock it actually resolved under, not
+		// every netblock routed by the ASN. When that association is
+		// unknown (e.g. a legacy record with no preceding netblock), fall
+		// back to hanging the FQDN off the ASN node directly rather than
+		// fabricating a netblock edge.
+		for _, f := range asn.FQDNs {
+			fqdnNode := fmt.Sprintf("%q", f.Name)
+			parent := asnNode
+			if f.Netblock != "" {
+				parent = fmt.Sprintf("%q", f.Netblock)
+			}
+			if _, err := fmt.Fprintf(out, "\t%s -> %s;\n", parent, fqdnNode); err != nil {
+				return err
+			}
+			for _, addr := range f.Addresses {
+				if _, err := fmt.Fprintf(out, "\t%s -> %q;\n", fqdnNode, addr); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	_, err := fmt.Fprintln(out, "}")
+	return err
+}
@@ -0,0 +1,122 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func twoNetblockGraph() *EnumGraph {
+	return &EnumGraph{
+		Target: "example.com",
+		Total:  2,
+		ASNs: []ASN{
+			{
+				ID:           "1111",
+				Organization: "Org One",
+				Netblocks: []Netblock{
+					{CIDR: "192.0.2.0/24"},
+					{CIDR: "198.51.100.0/24"},
+				},
+				FQDNs: []FQDN{
+					{Name: "one.example.com", Addresses: []string{"192.0.2.10"}, Netblock: "192.0.2.0/24"},
+					{Name: "two.example.com", Addresses: []string{"198.51.100.10"}, Netblock: "198.51.100.0/24"},
+				},
+			},
+		},
+	}
+}
+
+func TestDOTFormatDoesNotWireFQDNsToEveryNetblock(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (DOTFormat{}).Write(&buf, twoNetblockGraph()); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, `"192.0.2.0/24" -> "one.example.com"`) {
+		t.Error("expected one.example.com wired to its own netblock")
+	}
+	if !strings.Contains(out, `"198.51.100.0/24" -> "two.example.com"`) {
+		t.Error("expected two.example.com wired to its own netblock")
+	}
+	if strings.Contains(out, `"192.0.2.0/24" -> "two.example.com"`) {
+		t.Error("two.example.com should not be wired to a netblock it didn't resolve under")
+	}
+	if strings.Contains(out, `"198.51.100.0/24" -> "one.example.com"`) {
+		t.Error("one.example.com should not be wired to a netblock it didn't resolve under")
+	}
+}
+
+func TestJSONFormatUsesLowercaseFieldNamesLikeJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONFormat{}).Write(&buf, twoNetblockGraph()); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	for _, field := range []string{"target", "total", "asns"} {
+		if _, ok := decoded[field]; !ok {
+			t.Errorf("expected top-level field %q, got %v", field, decoded)
+		}
+	}
+
+	asns, ok := decoded["asns"].([]interface{})
+	if !ok || len(asns) == 0 {
+		t.Fatalf("expected a non-empty asns array, got %v", decoded["asns"])
+	}
+	asn, ok := asns[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected asns[0] to be an object, got %v", asns[0])
+	}
+	for _, field := range []string{"id", "organization", "netblocks", "fqdns"} {
+		if _, ok := asn[field]; !ok {
+			t.Errorf("expected ASN field %q, got %v", field, asn)
+		}
+	}
+}
+
+func TestJSONLFormatEmitsOneLinePerFQDN(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONLFormat{}).Write(&buf, twoNetblockGraph()); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per FQDN, got %d lines:\n%s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		var rec jsonFQDN
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line is not valid JSON: %v", err)
+		}
+		if rec.Name == "" || rec.ASN == "" {
+			t.Errorf("decoded record missing expected fields: %+v", rec)
+		}
+	}
+}
+
+func TestCSVFormatUsesPerFQDNNetblock(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (CSVFormat{}).Write(&buf, twoNetblockGraph()); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "one.example.com,192.0.2.10,1111,192.0.2.0/24,Org One") {
+		t.Errorf("expected row for one.example.com with its own netblock, got:\n%s", out)
+	}
+	if !strings.Contains(out, "two.example.com,198.51.100.10,1111,198.51.100.0/24,Org One") {
+		t.Errorf("expected row for two.example.com with its own netblock, got:\n%s", out)
+	}
+}
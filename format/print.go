@@ -59,159 +59,89 @@ type ASNSummaryData struct {
 	Netblocks map[string]int
 }
 
-func PrintEnumerationSummary(total int, records []string, target string) {
-	// Maps to hold the summarized data
-	asns := make(map[string]map[string]interface{}) // ASN -> (organization, netblocks, FQDNs)
-	fqdns := make(map[string]string)                // FQDN -> IP
-
-	// Parse the records
-	for _, record := range records {
-		parts := strings.Split(record, " --> ")
-		if len(parts) < 3 {
-			continue // Skip malformed records
-		}
-
-		left := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[2])
-
-		// Check if the record is an ASN
-		if strings.HasSuffix(value, " (Netblock)") {
-			// If it's a netblock, associate it with the ASN
-			ntblocks := strings.TrimSuffix(value, " (Netblock)")
-			for asnID := range asns {
-				asns[asnID]["netblocks"] = append(asns[asnID]["netblocks"].([]string), ntblocks)
-			}
-		} else if strings.HasSuffix(left, "(ASN)") {
-			asnID := left[:len(left)-len(" (ASN)")]
-			asnDetails := strings.Split(value, " ")
-			if len(asnDetails) >= 2 && strings.HasSuffix(value, "(RIROrganization)") {
-				asns[asnID] = map[string]interface{}{
-					"organization": strings.TrimSuffix(value, " (RIROrganization)"),
-					"netblocks":    []string{},
-					"fqdns":        []string{},
-				}
-			}
-		} else if strings.HasSuffix(left, "(FQDN)") {
-			// If it's a FQDN or IP address, store it
-			if strings.HasSuffix(left, "(FQDN)") && strings.HasSuffix(value, "(IPAddress)") {
-				fqdns[left] = value
-				// Associate FQDN with the ASN
-				for asnID := range asns {
-					asns[asnID]["fqdns"] = append(asns[asnID]["fqdns"].([]string), left)
-				}
-			} else {
-				fqdns[left] = value
-			} 
-		} 
+// PrintEnumerationSummary writes the report built from g to a file per
+// requested format, named after the target, the current date, and the
+// format's extension. When formats is empty, it falls back to the original
+// human-readable text report. A non-nil policy pseudonymizes g before any
+// format renders it.
+func PrintEnumerationSummary(g *EnumGraph, formats []OutputFormat, policy RedactionPolicy) {
+	if len(formats) == 0 {
+		formats = []OutputFormat{TextFormat{}}
 	}
+	g = RedactGraph(g, policy)
 
-	// pad := func(num int, chr string) {
-	// 	for i := 0; i < num; i++ {
-	// 		b.Fprint(color.Error, chr)
-	// 	}
-	// }
-
-	// fmt.Fprintln(color.Error)
-	// // Print the header information
-	// title := "OWASP Amass "
-	// site := "https://github.com/insomn14/amass"
-	// b.Fprint(color.Error, title+Version)
-	// num := 80 - (len(title) + len(Version) + len(site))
-	// pad(num, " ")
-	// b.Fprintf(color.Error, "%s\n", site)
-	// pad(8, "----------")
-	// fmt.Fprintf(color.Error, "\n%s%s", yellow(strconv.Itoa(total)), green(" records discovered"))
-	// fmt.Fprintln(color.Error)
-
-	// if len(asns) == 0 {
-	// 	return
-	// }
-	// // Another line gets printed
-	// pad(8, "----------")
-	// fmt.Fprintln(color.Error)
-
-	// Print the summary
-	// for asnID, details := range asns {
-	// 	// Print ASN details
-	// 	netblocks := strings.Join(details["netblocks"].([]string), ", ")
-	// 	org := details["organization"]
-	// 	fmt.Fprintf(color.Error, "\n%s%s - %s \n\t %s\t %s  %s\n", blue("ASN: "), yellow(asnID), green(org), yellow(netblocks), yellow(strconv.Itoa(len(fqdns))), blue("Subdomain Name(s)"))
-	// 	for fqdn, ip := range fqdns {
-	// 		if strings.HasSuffix(ip, "(FQDN)") {
-	// 			// Clean FQDN -> FQDN to FQDN -> IPAddress
-	// 			tmp_ip := fqdns[ip]
-	// 			fmt.Fprintf(color.Error, "\n%s --> %s", green(strings.TrimSuffix(fqdn, " (FQDN)")), yellow(strings.TrimSuffix(tmp_ip, " (IPAddress)")))
-	// 		} else {
-	// 			fmt.Fprintf(color.Error, "\n%s --> %s", green(strings.TrimSuffix(fqdn, " (FQDN)")), yellow(strings.TrimSuffix(ip, " (IPAddress)")))
-	// 		}
-	// 	}
-	// }
-	// PrintASNDetails(asns, fqdns)
-
-	// Generate dynamic filename with current date
 	currentDate := time.Now().Format("2006-01-02")
-	filename := fmt.Sprintf("%s_%s.txt", target, currentDate)
-	if err := SaveASNDetailsToFile(filename, asns, fqdns); err != nil {
-		color.Red("\n[!] Error saving file: %v", err)
-	} else {
+	for _, of := range formats {
+		filename := fmt.Sprintf("%s_%s.%s", g.Target, currentDate, of.Extension())
+		if err := saveOutputFormatToFile(filename, of, g); err != nil {
+			color.Red("\n[!] Error saving file: %v", err)
+			continue
+		}
 		color.Green("\n[+] Details saved to %s", filename)
 	}
 }
 
-// PrintASNDetails prints ASN details to the console
-func PrintASNDetails(asns map[string]map[string]interface{}, fqdns map[string]string) {
-	for asnID, details := range asns {
-		// Print ASN details
-		org := details["organization"].(string)
-		netblocks := strings.Join(details["netblocks"].([]string), ", ")
-		fmt.Fprintf(color.Error, "\n%s%s - %s\n\t%s%s\t%s%s\n",
-			color.BlueString("ASN: "), color.YellowString(asnID), color.GreenString(org),
-			color.YellowString(netblocks), color.YellowString(strconv.Itoa(len(fqdns))), color.BlueString(" Subdomain Name(s)"))
-
-		// Print FQDNs and associated IPs
-		for fqdn, ip := range fqdns {
-			if strings.HasSuffix(ip, "(FQDN)") {
-				tmpIP := fqdns[ip]
-				fmt.Fprintf(color.Error, "\n%s --> %s",
-					color.GreenString(strings.TrimSuffix(fqdn, " (FQDN)")),
-					color.YellowString(strings.TrimSuffix(tmpIP, " (IPAddress)")))
-			} else {
-				fmt.Fprintf(color.Error, "\n%s --> %s",
-					color.GreenString(strings.TrimSuffix(fqdn, " (FQDN)")),
-					color.YellowString(strings.TrimSuffix(ip, " (IPAddress)")))
+// saveOutputFormatToFile creates filename and renders g into it using of.
+func saveOutputFormatToFile(filename string, of OutputFormat, g *EnumGraph) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %v", err)
+	}
+	defer file.Close()
+
+	return of.Write(file, g)
+}
+
+// PrintASNDetails prints ASN details to the console. A non-nil policy
+// pseudonymizes g before it is printed.
+func PrintASNDetails(g *EnumGraph, policy RedactionPolicy) {
+	g = RedactGraph(g, policy)
+
+	for _, asn := range g.ASNs {
+		netblocks := make([]string, len(asn.Netblocks))
+		for i, nb := range asn.Netblocks {
+			netblocks[i] = nb.CIDR
+		}
+		fmt.Fprintf(color.Error, "\n%s%s - %s\n\t%s%s%s\n",
+			color.BlueString("ASN: "), color.YellowString(asn.ID), color.GreenString(asn.Organization),
+			color.YellowString(strings.Join(netblocks, ", ")), color.YellowString(strconv.Itoa(len(asn.FQDNs))),
+			color.BlueString(" Subdomain Name(s)"))
+
+		for _, f := range asn.FQDNs {
+			for _, addr := range f.Addresses {
+				fmt.Fprintf(color.Error, "\n%s --> %s", color.GreenString(f.Name), color.YellowString(addr))
 			}
 		}
 	}
 	fmt.Fprintln(color.Error)
 }
 
-func SaveASNDetailsToFile(filename string, asns map[string]map[string]interface{}, fqdns map[string]string) error {
+// SaveASNDetailsToFile writes the ASN/netblock/FQDN report carried by g to
+// filename. A non-nil policy pseudonymizes g before it is written.
+func SaveASNDetailsToFile(filename string, g *EnumGraph, policy RedactionPolicy) error {
+	g = RedactGraph(g, policy)
+
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %v", err)
 	}
 	defer file.Close()
 
-	for asnID, details := range asns {
-		// Write ASN details
-		org := details["organization"].(string)
-		netblocks := strings.Join(details["netblocks"].([]string), ", ")
-		_, err := file.WriteString(fmt.Sprintf("ASN: %s - %s\n\tNetblocks: %s\n\tSubdomains: %d\n", asnID, org, netblocks, len(fqdns)))
-		if err != nil {
+	for _, asn := range g.ASNs {
+		netblocks := make([]string, len(asn.Netblocks))
+		for i, nb := range asn.Netblocks {
+			netblocks[i] = nb.CIDR
+		}
+		if _, err := file.WriteString(fmt.Sprintf("ASN: %s - %s\n\tNetblocks: %s\n\tSubdomains: %d\n",
+			asn.ID, asn.Organization, strings.Join(netblocks, ", "), len(asn.FQDNs))); err != nil {
 			return fmt.Errorf("failed to write ASN details: %v", err)
 		}
 
-		// Write FQDNs and associated IPs
-		for fqdn, ip := range fqdns {
-			var line string
-			if strings.HasSuffix(ip, "(FQDN)") {
-				tmpIP := fqdns[ip]
-				line = fmt.Sprintf("%s: %s\n", strings.TrimSuffix(fqdn, " (FQDN)"), strings.TrimSuffix(tmpIP, " (IPAddress)"))
-			} else {
-				line = fmt.Sprintf("%s: %s\n", strings.TrimSuffix(fqdn, " (FQDN)"), strings.TrimSuffix(ip, " (IPAddress)"))
-			}
-			if _, err := file.WriteString(line); err != nil {
-				return fmt.Errorf("failed to write FQDN details: %v", err)
+		for _, f := range asn.FQDNs {
+			for _, addr := range f.Addresses {
+				if _, err := file.WriteString(fmt.Sprintf("%s: %s\n", f.Name, addr)); err != nil {
+					return fmt.Errorf("failed to write FQDN details: %v", err)
+				}
 			}
 		}
 	}
@@ -238,14 +168,10 @@ func UpdateSummaryData(output *requests.Output, asns map[int]*ASNSummaryData) {
 	}
 }
 
-// PrintEnumerationSummary outputs the summary information utilized by the command-line tools.
-// func PrintEnumerationSummary(total int, asns map[int]*ASNSummaryData, demo bool) {
-// 	FprintEnumerationSummary(color.Error, total, asns, demo)
-// }
-
-
-// FprintEnumerationSummary outputs the summary information utilized by the command-line tools.
-func FprintEnumerationSummary(out io.Writer, total int, asns map[int]*ASNSummaryData, demo bool) {
+// FprintEnumerationSummary outputs the summary information utilized by the
+// command-line tools. A non-nil policy pseudonymizes ASN numbers, names, and
+// netblocks before they're printed; ASN 0 and its name are printed as is.
+func FprintEnumerationSummary(out io.Writer, total int, asns map[int]*ASNSummaryData, policy RedactionPolicy) {
 	pad := func(num int, chr string) {
 		for i := 0; i < num; i++ {
 			b.Fprint(out, chr)
@@ -275,9 +201,8 @@ func FprintEnumerationSummary(out io.Writer, total int, asns map[int]*ASNSummary
 		asnstr := strconv.Itoa(asn)
 		datastr := data.Name
 
-		if demo && asn > 0 {
-			asnstr = censorString(asnstr, 0, len(asnstr))
-			datastr = censorString(datastr, 0, len(datastr))
+		if policy != nil && asn > 0 {
+			asnstr, datastr = policy.RedactASN(asnstr, datastr)
 		}
 		fmt.Fprintf(out, "%s%s %s %s\n", blue("ASN: "), yellow(asnstr), green("-"), green(datastr))
 
@@ -285,8 +210,8 @@ func FprintEnumerationSummary(out io.Writer, total int, asns map[int]*ASNSummary
 			countstr := strconv.Itoa(ips)
 			cidrstr := cidr
 
-			if demo {
-				cidrstr = censorNetBlock(cidrstr)
+			if policy != nil {
+				cidrstr = policy.RedactNetBlock(cidrstr)
 			}
 
 			countstr = fmt.Sprintf("\t%-4s", countstr)
@@ -321,18 +246,6 @@ func FprintBanner(out io.Writer) {
 	_, _ = y.Fprintf(out, "%s\n\n\n", Description)
 }
 
-func censorDomain(input string) string {
-	return censorString(input, strings.Index(input, "."), len(input))
-}
-
-func censorIP(input string) string {
-	return censorString(input, 0, strings.LastIndex(input, "."))
-}
-
-func censorNetBlock(input string) string {
-	return censorString(input, 0, strings.Index(input, "/"))
-}
-
 func censorString(input string, start, end int) string {
 	runes := []rune(input)
 	for i := start; i < end; i++ {
@@ -347,28 +260,32 @@ func censorString(input string, start, end int) string {
 	return string(runes)
 }
 
-// OutputLineParts returns the parts of a line to be printed for a requests.Output.
-func OutputLineParts(out *requests.Output, addrs, demo bool) (name, ips string) {
+// OutputLineParts returns the parts of a line to be printed for a
+// requests.Output. A non-nil policy pseudonymizes the name and addresses.
+func OutputLineParts(out *requests.Output, addrs bool, policy RedactionPolicy) (name, ips string) {
 	if addrs {
 		for i, a := range out.Addresses {
 			if i != 0 {
 				ips += ","
 			}
-			if demo {
-				ips += censorIP(a.Address.String())
+			if policy != nil {
+				ips += policy.RedactIP(a.Address.String())
 			} else {
 				ips += a.Address.String()
 			}
 		}
 	}
 	name = out.Name
-	if demo {
-		name = censorDomain(name)
+	if policy != nil {
+		name = policy.RedactDomain(name)
 	}
 	return
 }
 
-func OutputLinePartsOld(out *requests.Output, src, addrs, demo bool) (source, name, ips string) {
+// OutputLinePartsOld returns the parts of a line to be printed for a
+// requests.Output, in the format used before OutputLineParts. A non-nil
+// policy pseudonymizes the name and addresses.
+func OutputLinePartsOld(out *requests.Output, src, addrs bool, policy RedactionPolicy) (source, name, ips string) {
 	if src {
 		source = fmt.Sprintf("%-18s", "["+out.Sources[0]+"] ")
 	}
@@ -377,8 +294,8 @@ func OutputLinePartsOld(out *requests.Output, src, addrs, demo bool) (source, na
 			if i != 0 {
 				ips += ","
 			}
-			if demo {
-				ips += censorIP(a.Address.String())
+			if policy != nil {
+				ips += policy.RedactIP(a.Address.String())
 			} else {
 				ips += a.Address.String()
 			}
@@ -388,8 +305,8 @@ func OutputLinePartsOld(out *requests.Output, src, addrs, demo bool) (source, na
 		}
 	}
 	name = out.Name
-	if demo {
-		name = censorDomain(name)
+	if policy != nil {
+		name = policy.RedactDomain(name)
 	}
 	return
 }
@@ -0,0 +1,253 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/term"
+)
+
+// progressSnapshot is a point-in-time view of the counters tracked by a
+// ProgressReporter, suitable for rendering to a TTY or encoding as a status
+// line for non-interactive consumers.
+type progressSnapshot struct {
+	Timestamp    string         `json:"timestamp"`
+	SourceCounts map[string]int `json:"source_counts"`
+	ResolverQPS  float64        `json:"resolver_qps"`
+	UniqueFQDNs  int            `json:"unique_fqdns"`
+	ASNsSeen     int            `json:"asns_seen"`
+	QueueDepth   int            `json:"queue_depth"`
+}
+
+// ProgressReporter renders live enumeration health - per-source request
+// counts, resolver QPS, unique FQDNs discovered, ASNs seen, and queue depth -
+// on an interval. It replaces the prior behavior of waiting until the run
+// completes before showing anything, which made multi-hour scans appear
+// stalled. On a TTY it draws an in-place dashboard; otherwise it writes
+// periodic JSON status lines suitable for CI/log aggregators.
+type ProgressReporter struct {
+	out      io.Writer
+	isTTY    bool
+	interval time.Duration
+
+	// linesDrawn is only touched from render(), which Start calls serially
+	// off a single ticker goroutine, so it needs no locking of its own.
+	linesDrawn int
+
+	mu           sync.Mutex
+	sourceCounts map[string]int
+	resolverQPS  float64
+	uniqueFQDNs  int
+	asnsSeen     int
+	queueDepth   int
+	metrics      *progressMetrics
+}
+
+// NewProgressReporter returns a reporter that writes to out every interval.
+// TTY detection determines whether it draws a redrawing dashboard or emits
+// JSON status lines.
+func NewProgressReporter(out io.Writer, interval time.Duration) *ProgressReporter {
+	isTTY := false
+	if f, ok := out.(*os.File); ok {
+		isTTY = term.IsTerminal(int(f.Fd()))
+	}
+
+	return &ProgressReporter{
+		out:          out,
+		isTTY:        isTTY,
+		interval:     interval,
+		sourceCounts: make(map[string]int),
+	}
+}
+
+// IncSourceRequest records a single request made against a named data source.
+func (p *ProgressReporter) IncSourceRequest(name string) {
+	p.mu.Lock()
+	p.sourceCounts[name]++
+	metrics := p.metrics
+	p.mu.Unlock()
+	if metrics != nil {
+		metrics.sourceRequests.WithLabelValues(name).Inc()
+	}
+}
+
+// SetResolverQPS records the current DNS resolver queries-per-second rate.
+func (p *ProgressReporter) SetResolverQPS(qps float64) {
+	p.mu.Lock()
+	p.resolverQPS = qps
+	metrics := p.metrics
+	p.mu.Unlock()
+	if metrics != nil {
+		metrics.resolverQPS.Set(qps)
+	}
+}
+
+// SetUniqueFQDNs records the number of unique FQDNs discovered so far.
+func (p *ProgressReporter) SetUniqueFQDNs(n int) {
+	p.mu.Lock()
+	p.uniqueFQDNs = n
+	metrics := p.metrics
+	p.mu.Unlock()
+	if metrics != nil {
+		metrics.uniqueFQDNs.Set(float64(n))
+	}
+}
+
+// SetASNsSeen records the number of distinct ASNs mapped so far.
+func (p *ProgressReporter) SetASNsSeen(n int) {
+	p.mu.Lock()
+	p.asnsSeen = n
+	metrics := p.metrics
+	p.mu.Unlock()
+	if metrics != nil {
+		metrics.asnsSeen.Set(float64(n))
+	}
+}
+
+// SetQueueDepth records the current depth of the enumeration work queue.
+func (p *ProgressReporter) SetQueueDepth(n int) {
+	p.mu.Lock()
+	p.queueDepth = n
+	metrics := p.metrics
+	p.mu.Unlock()
+	if metrics != nil {
+		metrics.queueDepth.Set(float64(n))
+	}
+}
+
+func (p *ProgressReporter) snapshot() progressSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	counts := make(map[string]int, len(p.sourceCounts))
+	for k, v := range p.sourceCounts {
+		counts[k] = v
+	}
+
+	return progressSnapshot{
+		SourceCounts: counts,
+		ResolverQPS:  p.resolverQPS,
+		UniqueFQDNs:  p.uniqueFQDNs,
+		ASNsSeen:     p.asnsSeen,
+		QueueDepth:   p.queueDepth,
+	}
+}
+
+// Start begins rendering on the configured interval until ctx is canceled.
+func (p *ProgressReporter) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.render()
+		}
+	}
+}
+
+func (p *ProgressReporter) render() {
+	snap := p.snapshot()
+
+	if !p.isTTY {
+		snap.Timestamp = time.Now().Format(time.RFC3339)
+		_ = json.NewEncoder(p.out).Encode(snap)
+		return
+	}
+
+	// Move the cursor back to the top of the previously drawn dashboard
+	// before redrawing, so it updates in place rather than scrolling. This
+	// must use the line count from the last frame actually drawn, not the
+	// current frame - the number of active data sources changes as sources
+	// start and finish, so the two can differ. Skip the erase entirely on
+	// the first frame, since nothing has been drawn yet to erase.
+	if p.linesDrawn > 0 {
+		fmt.Fprintf(p.out, "\033[%dA\033[J", p.linesDrawn)
+	}
+
+	fmt.Fprintf(p.out, "%s %s   %s %s   %s %s\n",
+		blue("FQDNs:"), green(snap.UniqueFQDNs),
+		blue("ASNs:"), green(snap.ASNsSeen),
+		blue("Queue:"), yellow(snap.QueueDepth))
+	fmt.Fprintf(p.out, "%s %.1f/s\n", blue("Resolver QPS:"), snap.ResolverQPS)
+	fmt.Fprintln(p.out, blue("Data sources:"))
+	for name, count := range snap.SourceCounts {
+		fmt.Fprintf(p.out, "  %s %s\n", yellow(fmt.Sprintf("%-20s", name)), green(count))
+	}
+	fmt.Fprintln(p.out)
+
+	p.linesDrawn = 3 + len(snap.SourceCounts) + 1
+}
+
+// progressMetrics holds the Prometheus collectors exposed by
+// StartMetricsServer, so long-running Amass daemons can be scraped.
+type progressMetrics struct {
+	sourceRequests *prometheus.CounterVec
+	resolverQPS    prometheus.Gauge
+	uniqueFQDNs    prometheus.Gauge
+	asnsSeen       prometheus.Gauge
+	queueDepth     prometheus.Gauge
+}
+
+// StartMetricsServer registers Prometheus gauges/counters for this
+// reporter's counters and serves them on addr at /metrics. It binds addr
+// synchronously, so a port already in use is reported to the caller instead
+// of being silently swallowed in a background goroutine.
+func (p *ProgressReporter) StartMetricsServer(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start metrics server: %v", err)
+	}
+
+	reg := prometheus.NewRegistry()
+	metrics := &progressMetrics{
+		sourceRequests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "amass_source_requests_total",
+			Help: "Number of requests made to each data source.",
+		}, []string{"source"}),
+		resolverQPS: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "amass_resolver_qps",
+			Help: "Current DNS resolver queries per second.",
+		}),
+		uniqueFQDNs: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "amass_unique_fqdns",
+			Help: "Number of unique FQDNs discovered so far.",
+		}),
+		asnsSeen: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "amass_asns_seen",
+			Help: "Number of distinct ASNs mapped so far.",
+		}),
+		queueDepth: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "amass_queue_depth",
+			Help: "Current depth of the enumeration work queue.",
+		}),
+	}
+
+	p.mu.Lock()
+	p.metrics = metrics
+	p.mu.Unlock()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	go func() {
+		_ = http.Serve(ln, mux)
+	}()
+	return nil
+}
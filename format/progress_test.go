@@ -0,0 +1,51 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressReporterFirstRenderDoesNotErase(t *testing.T) {
+	var buf bytes.Buffer
+	p := &ProgressReporter{
+		out:          &buf,
+		isTTY:        true,
+		interval:     time.Second,
+		sourceCounts: map[string]int{"crtsh": 1},
+	}
+
+	p.render()
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("first render should not emit a cursor-erase escape sequence, got %q", buf.String())
+	}
+}
+
+func TestProgressReporterErasesPriorFrameLineCount(t *testing.T) {
+	var buf bytes.Buffer
+	p := &ProgressReporter{
+		out:          &buf,
+		isTTY:        true,
+		interval:     time.Second,
+		sourceCounts: map[string]int{"crtsh": 1, "shodan": 2},
+	}
+
+	p.render()
+	firstFrameLines := p.linesDrawn
+
+	// Drop a source between frames, mimicking a data source finishing.
+	p.sourceCounts = map[string]int{"crtsh": 1}
+	buf.Reset()
+	p.render()
+
+	want := "\033[" + strconv.Itoa(firstFrameLines) + "A\033[J"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("expected second render to erase using the prior frame's line count %d, got %q", firstFrameLines, buf.String())
+	}
+}
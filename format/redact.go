@@ -0,0 +1,201 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strings"
+)
+
+// RedactionPolicy pseudonymizes FQDNs, IPs, and netblocks before they reach a
+// printer or OutputFormat, so an engagement report can be shared without
+// disclosing the underlying assets. It replaces the single hardcoded
+// "x"-substitution strategy that censorDomain/censorIP/censorNetBlock used to
+// provide, with pluggable strategies selectable via -demo-policy.
+type RedactionPolicy interface {
+	// Name identifies the policy, e.g. for selection via -demo-policy.
+	Name() string
+	RedactDomain(fqdn string) string
+	RedactIP(ip string) string
+	RedactNetBlock(cidr string) string
+	// RedactASN pseudonymizes an ASN number and its RIR organization name.
+	RedactASN(id, org string) (string, string)
+}
+
+// NoRedaction passes every value through unchanged. It is used when demo
+// mode is disabled, so callers can apply a RedactionPolicy unconditionally.
+type NoRedaction struct{}
+
+// Name implements the RedactionPolicy interface.
+func (NoRedaction) Name() string { return "none" }
+
+// RedactDomain implements the RedactionPolicy interface.
+func (NoRedaction) RedactDomain(fqdn string) string { return fqdn }
+
+// RedactIP implements the RedactionPolicy interface.
+func (NoRedaction) RedactIP(ip string) string { return ip }
+
+// RedactNetBlock implements the RedactionPolicy interface.
+func (NoRedaction) RedactNetBlock(cidr string) string { return cidr }
+
+// RedactASN implements the RedactionPolicy interface.
+func (NoRedaction) RedactASN(id, org string) (string, string) { return id, org }
+
+// FullMaskPolicy replaces runes with 'x', keeping the separators ('.', '/',
+// '-', ' ') intact. It leaves the first label of an FQDN (e.g.
+// "mail.example.com" becomes "mail.xxxxxxx.xxx"; a bare hostname is fully
+// masked), the last octet or group of an IP address, and the prefix length
+// of a netblock unmasked; ASN numbers and names are masked in full. Unlike
+// the original censorIP, it is
+// IPv6-aware: censorIP used strings.LastIndex(ip, ".") to find where the
+// address portion ended, which produced garbage for IPv6 addresses that
+// contain no dots at all.
+type FullMaskPolicy struct{}
+
+// Name implements the RedactionPolicy interface.
+func (FullMaskPolicy) Name() string { return "full" }
+
+// RedactDomain implements the RedactionPolicy interface.
+func (FullMaskPolicy) RedactDomain(fqdn string) string {
+	idx := strings.Index(fqdn, ".")
+	if idx < 0 {
+		idx = 0
+	}
+	return censorString(fqdn, idx, len(fqdn))
+}
+
+// RedactIP implements the RedactionPolicy interface.
+func (FullMaskPolicy) RedactIP(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		return censorString(ip, 0, strings.LastIndex(ip, ":"))
+	}
+	return censorString(ip, 0, strings.LastIndex(ip, "."))
+}
+
+// RedactNetBlock implements the RedactionPolicy interface.
+func (FullMaskPolicy) RedactNetBlock(cidr string) string {
+	return censorString(cidr, 0, strings.Index(cidr, "/"))
+}
+
+// RedactASN implements the RedactionPolicy interface.
+func (FullMaskPolicy) RedactASN(id, org string) (string, string) {
+	return censorString(id, 0, len(id)), censorString(org, 0, len(org))
+}
+
+// KeepTLDPolicy masks every FQDN label except the top-level domain, e.g.
+// "mail.corp.example.com" becomes "xxxx.xxxx.xxxxxxx.com".
+type KeepTLDPolicy struct{}
+
+// Name implements the RedactionPolicy interface.
+func (KeepTLDPolicy) Name() string { return "keep-tld" }
+
+// RedactDomain implements the RedactionPolicy interface.
+func (KeepTLDPolicy) RedactDomain(fqdn string) string {
+	idx := strings.LastIndex(fqdn, ".")
+	if idx < 0 {
+		return censorString(fqdn, 0, len(fqdn))
+	}
+	return censorString(fqdn, 0, idx)
+}
+
+// RedactIP implements the RedactionPolicy interface.
+func (KeepTLDPolicy) RedactIP(ip string) string { return (FullMaskPolicy{}).RedactIP(ip) }
+
+// RedactNetBlock implements the RedactionPolicy interface.
+func (KeepTLDPolicy) RedactNetBlock(cidr string) string { return (FullMaskPolicy{}).RedactNetBlock(cidr) }
+
+// RedactASN implements the RedactionPolicy interface.
+func (KeepTLDPolicy) RedactASN(id, org string) (string, string) { return (FullMaskPolicy{}).RedactASN(id, org) }
+
+// Keep2LDPolicy masks every FQDN label except the registrable second-level
+// domain, e.g. "mail.corp.example.com" becomes "xxxx.xxxx.example.com".
+type Keep2LDPolicy struct{}
+
+// Name implements the RedactionPolicy interface.
+func (Keep2LDPolicy) Name() string { return "keep-2ld" }
+
+// RedactDomain implements the RedactionPolicy interface.
+func (Keep2LDPolicy) RedactDomain(fqdn string) string {
+	labels := strings.Split(fqdn, ".")
+	if len(labels) < 2 {
+		return censorString(fqdn, 0, len(fqdn))
+	}
+	keep := strings.Join(labels[len(labels)-2:], ".")
+	return censorString(fqdn, 0, len(fqdn)-len(keep))
+}
+
+// RedactIP implements the RedactionPolicy interface.
+func (Keep2LDPolicy) RedactIP(ip string) string { return (FullMaskPolicy{}).RedactIP(ip) }
+
+// RedactNetBlock implements the RedactionPolicy interface.
+func (Keep2LDPolicy) RedactNetBlock(cidr string) string { return (FullMaskPolicy{}).RedactNetBlock(cidr) }
+
+// RedactASN implements the RedactionPolicy interface.
+func (Keep2LDPolicy) RedactASN(id, org string) (string, string) { return (FullMaskPolicy{}).RedactASN(id, org) }
+
+// HMACPolicy replaces each value with an HMAC-SHA256 token keyed by a
+// user-supplied secret, so the same FQDN or IP maps to the same token across
+// multiple runs - allowing correlation across an engagement - without
+// disclosing the original value to anyone who doesn't hold Key.
+type HMACPolicy struct {
+	Key []byte
+}
+
+// Name implements the RedactionPolicy interface.
+func (p HMACPolicy) Name() string { return "hmac" }
+
+func (p HMACPolicy) token(value string) string {
+	mac := hmac.New(sha256.New, p.Key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// RedactDomain implements the RedactionPolicy interface.
+func (p HMACPolicy) RedactDomain(fqdn string) string { return p.token(fqdn) + ".redacted" }
+
+// RedactIP implements the RedactionPolicy interface.
+func (p HMACPolicy) RedactIP(ip string) string { return p.token(ip) }
+
+// RedactNetBlock implements the RedactionPolicy interface.
+func (p HMACPolicy) RedactNetBlock(cidr string) string { return p.token(cidr) }
+
+// RedactASN implements the RedactionPolicy interface.
+func (p HMACPolicy) RedactASN(id, org string) (string, string) { return p.token(id), p.token(org) }
+
+// RedactGraph returns a copy of g with every FQDN, address, and netblock run
+// through policy. A nil policy returns g unchanged.
+func RedactGraph(g *EnumGraph, policy RedactionPolicy) *EnumGraph {
+	if policy == nil {
+		return g
+	}
+
+	out := &EnumGraph{Target: g.Target, Total: g.Total}
+	for _, asn := range g.ASNs {
+		id, org := policy.RedactASN(asn.ID, asn.Organization)
+		redacted := ASN{ID: id, Organization: org}
+
+		for _, nb := range asn.Netblocks {
+			redacted.Netblocks = append(redacted.Netblocks, Netblock{CIDR: policy.RedactNetBlock(nb.CIDR)})
+		}
+
+		for _, f := range asn.FQDNs {
+			rf := FQDN{Name: policy.RedactDomain(f.Name)}
+			if f.Netblock != "" {
+				rf.Netblock = policy.RedactNetBlock(f.Netblock)
+			}
+			for _, addr := range f.Addresses {
+				rf.Addresses = append(rf.Addresses, policy.RedactIP(addr))
+			}
+			redacted.FQDNs = append(redacted.FQDNs, rf)
+		}
+
+		out.ASNs = append(out.ASNs, redacted)
+	}
+
+	return out
+}
@@ -0,0 +1,62 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import "testing"
+
+func TestFullMaskPolicyRedactDomainHandlesBareHostname(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("RedactDomain panicked on a bare hostname: %v", r)
+		}
+	}()
+
+	got := (FullMaskPolicy{}).RedactDomain("localhost")
+	if got != "xxxxxxxxx" {
+		t.Errorf("RedactDomain(%q) = %q, want all runes masked", "localhost", got)
+	}
+}
+
+func TestFullMaskPolicyRedactIPIsIPv6Aware(t *testing.T) {
+	policy := FullMaskPolicy{}
+
+	if got := policy.RedactIP("192.0.2.10"); got != "xxx.x.x.10" {
+		t.Errorf("RedactIP(IPv4) = %q, want xxx.x.x.10", got)
+	}
+	if got := policy.RedactIP("2001:db8::1"); got == "2001:db8::1" {
+		t.Errorf("RedactIP(IPv6) left the address unmasked: %q", got)
+	}
+}
+
+func TestHMACPolicyIsDeterministicAcrossCalls(t *testing.T) {
+	policy := HMACPolicy{Key: []byte("engagement-key")}
+
+	first := policy.RedactDomain("host.example.com")
+	second := policy.RedactDomain("host.example.com")
+	if first != second {
+		t.Errorf("HMACPolicy produced different tokens for the same input: %q vs %q", first, second)
+	}
+
+	if other := policy.RedactDomain("other.example.com"); other == first {
+		t.Error("HMACPolicy produced the same token for two different FQDNs")
+	}
+}
+
+func TestRedactGraphRedactsASNAndOrganization(t *testing.T) {
+	g := &EnumGraph{
+		Target: "example.com",
+		ASNs: []ASN{
+			{ID: "1111", Organization: "Example Org", Netblocks: []Netblock{{CIDR: "192.0.2.0/24"}}},
+		},
+	}
+
+	redacted := RedactGraph(g, FullMaskPolicy{})
+	if redacted.ASNs[0].ID == "1111" {
+		t.Error("RedactGraph left the ASN number unredacted")
+	}
+	if redacted.ASNs[0].Organization == "Example Org" {
+		t.Error("RedactGraph left the RIR organization name unredacted")
+	}
+}
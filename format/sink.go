@@ -0,0 +1,70 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import "context"
+
+// EnumEventType identifies the kind of discovery carried by an EnumEvent.
+type EnumEventType string
+
+const (
+	// EventNewFQDN indicates a previously unseen FQDN was discovered.
+	EventNewFQDN EnumEventType = "new_fqdn"
+	// EventNewAddress indicates an FQDN resolved to a new IP address.
+	EventNewAddress EnumEventType = "new_address"
+	// EventNewASN indicates a previously unseen ASN was mapped.
+	EventNewASN EnumEventType = "new_asn"
+	// EventNewNetblock indicates a netblock was associated with an ASN.
+	EventNewNetblock EnumEventType = "new_netblock"
+)
+
+// EnumEvent is a single incremental discovery made during enumeration. The
+// enumeration engine emits these as they happen, instead of only handing a
+// terminal summary to PrintEnumerationSummary, so downstream systems see
+// near-real-time surface changes on long-running scans.
+type EnumEvent struct {
+	Type     EnumEventType `json:"type"`
+	FQDN     string        `json:"fqdn,omitempty"`
+	Address  string        `json:"address,omitempty"`
+	ASN      string        `json:"asn,omitempty"`
+	Org      string        `json:"organization,omitempty"`
+	Netblock string        `json:"netblock,omitempty"`
+}
+
+// OutputSink receives incremental EnumEvents in near-real time, e.g. to
+// forward discoveries to a webhook, a syslog daemon, Elasticsearch, or
+// Kafka. Implementations must be safe for concurrent use, since the
+// enumeration engine may emit events from multiple goroutines.
+type OutputSink interface {
+	// Emit delivers a single event to the sink.
+	Emit(ctx context.Context, e EnumEvent) error
+	// Flush blocks until every event accepted so far has been durably handled.
+	Flush(ctx context.Context) error
+}
+
+// SinkConfig declares a single OutputSink, as parsed from the same YAML file
+// used to configure data sources. When Events is non-empty, only events
+// whose Type appears in it are delivered to the sink.
+type SinkConfig struct {
+	Type   string   `yaml:"type"`
+	URL    string   `yaml:"url,omitempty"`
+	Topic  string   `yaml:"topic,omitempty"`
+	Index  string   `yaml:"index,omitempty"`
+	Events []string `yaml:"events,omitempty"`
+}
+
+// Matches reports whether e should be delivered to a sink configured with c,
+// based on c.Events. An empty Events list matches every event type.
+func (c SinkConfig) Matches(e EnumEvent) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, t := range c.Events {
+		if EnumEventType(t) == e.Type {
+			return true
+		}
+	}
+	return false
+}
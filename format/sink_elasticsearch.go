@@ -0,0 +1,92 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ElasticsearchBulkSink batches EnumEvents and submits them to an
+// Elasticsearch _bulk endpoint once BatchSize events have accumulated or
+// Flush is called.
+type ElasticsearchBulkSink struct {
+	URL       string
+	Index     string
+	Client    *http.Client
+	BatchSize int
+
+	mu      sync.Mutex
+	pending []EnumEvent
+}
+
+// NewElasticsearchBulkSink returns a sink that bulk-indexes events into index
+// on the Elasticsearch server at url, batching up to batchSize events per
+// request.
+func NewElasticsearchBulkSink(url, index string, batchSize int) *ElasticsearchBulkSink {
+	return &ElasticsearchBulkSink{
+		URL:       url,
+		Index:     index,
+		Client:    &http.Client{},
+		BatchSize: batchSize,
+	}
+}
+
+// Emit queues e, flushing the batch once BatchSize events are pending.
+func (s *ElasticsearchBulkSink) Emit(ctx context.Context, e EnumEvent) error {
+	s.mu.Lock()
+	s.pending = append(s.pending, e)
+	full := len(s.pending) >= s.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		return s.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush submits all pending events as a single _bulk request.
+func (s *ElasticsearchBulkSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, e := range batch {
+		meta := map[string]interface{}{"index": map[string]string{"_index": s.Index}}
+		if err := json.NewEncoder(&buf).Encode(meta); err != nil {
+			return fmt.Errorf("failed to encode bulk meta: %v", err)
+		}
+		if err := json.NewEncoder(&buf).Encode(e); err != nil {
+			return fmt.Errorf("failed to encode event: %v", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL+"/_bulk", &buf)
+	if err != nil {
+		return fmt.Errorf("failed to build bulk request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("bulk request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("bulk request returned status %d", resp.StatusCode)
+	}
+	return nil
+}
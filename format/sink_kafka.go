@@ -0,0 +1,49 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// KafkaSink publishes EnumEvents to a Kafka topic, keyed by FQDN so events
+// for the same name land on the same partition and arrive in order.
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink returns a sink that publishes to topic on the given brokers.
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+		},
+	}
+}
+
+// Emit publishes e to the configured Kafka topic.
+func (s *KafkaSink) Emit(ctx context.Context, e EnumEvent) error {
+	value, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	return s.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(e.FQDN),
+		Value: value,
+	})
+}
+
+// Flush is a no-op: the writer is synchronous, so Emit returns only after
+// WriteMessages has finished and nothing is left buffered.
+func (s *KafkaSink) Flush(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogSink forwards EnumEvents to a syslog daemon via the standard
+// log/syslog client. That client uses BSD-style framing (<PRI>TIMESTAMP
+// HOSTNAME TAG[PID]: MSG to a remote daemon, without HOSTNAME on the local
+// socket) - there is no version field or structured-data section, so this
+// is not RFC 5424 compliant.
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink dials network/addr (e.g. "udp", "log.example.com:514") and
+// returns a sink that writes events there. Pass an empty network to use the
+// local syslog socket.
+func NewSyslogSink(network, addr, tag string) (*SyslogSink, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial syslog: %v", err)
+	}
+	return &SyslogSink{writer: w}, nil
+}
+
+// Emit writes e as an RFC 3164 informational message.
+func (s *SyslogSink) Emit(ctx context.Context, e EnumEvent) error {
+	return s.writer.Info(fmt.Sprintf("%s fqdn=%q address=%q asn=%q netblock=%q",
+		e.Type, e.FQDN, e.Address, e.ASN, e.Netblock))
+}
+
+// Flush is a no-op, since syslog writes are unbuffered.
+func (s *SyslogSink) Flush(ctx context.Context) error {
+	return nil
+}
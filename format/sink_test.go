@@ -0,0 +1,57 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import "testing"
+
+func TestSinkConfigMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SinkConfig
+		event  EnumEvent
+		want   bool
+	}{
+		{
+			name:   "empty Events matches every type",
+			config: SinkConfig{Type: "webhook"},
+			event:  EnumEvent{Type: EventNewFQDN},
+			want:   true,
+		},
+		{
+			name:   "matching type passes the filter",
+			config: SinkConfig{Type: "webhook", Events: []string{"new_fqdn", "new_asn"}},
+			event:  EnumEvent{Type: EventNewFQDN},
+			want:   true,
+		},
+		{
+			name:   "non-matching type is filtered out",
+			config: SinkConfig{Type: "webhook", Events: []string{"new_asn"}},
+			event:  EnumEvent{Type: EventNewFQDN},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Matches(tt.event); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookBackoffIsCappedAndIncreasing(t *testing.T) {
+	prev := webhookBackoff(1)
+	for attempt := 2; attempt <= 10; attempt++ {
+		cur := webhookBackoff(attempt)
+		if cur < prev {
+			t.Errorf("webhookBackoff(%d) = %v, expected >= webhookBackoff(%d) = %v", attempt, cur, attempt-1, prev)
+		}
+		prev = cur
+	}
+	if prev.Seconds() > 5 {
+		t.Errorf("webhookBackoff should be capped at 5s, got %v", prev)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPWebhookSink POSTs each EnumEvent as a JSON document to a configured
+// endpoint, retrying transient failures with a quadratically growing backoff.
+type HTTPWebhookSink struct {
+	URL        string
+	Client     *http.Client
+	MaxRetries int
+}
+
+// NewHTTPWebhookSink returns a sink that POSTs events to url.
+func NewHTTPWebhookSink(url string) *HTTPWebhookSink {
+	return &HTTPWebhookSink{
+		URL:        url,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+		MaxRetries: 3,
+	}
+}
+
+// Emit sends e to the webhook endpoint, retrying on 5xx responses and
+// transport errors. The wait before retry n is n*n*200ms, capped at 5s.
+func (s *HTTPWebhookSink) Emit(ctx context.Context, e EnumEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= s.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(webhookBackoff(attempt)):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to build webhook request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.Client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 500 {
+			if resp.StatusCode >= 300 {
+				return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+			}
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return fmt.Errorf("webhook failed after %d attempts: %v", s.MaxRetries+1, lastErr)
+}
+
+// Flush is a no-op, since HTTPWebhookSink delivers every event synchronously.
+func (s *HTTPWebhookSink) Flush(ctx context.Context) error {
+	return nil
+}
+
+func webhookBackoff(attempt int) time.Duration {
+	d := time.Duration(attempt*attempt) * 200 * time.Millisecond
+	if d > 5*time.Second {
+		d = 5 * time.Second
+	}
+	return d
+}